@@ -0,0 +1,309 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// EvictReason describes why an entry was removed from a Cache.
+type EvictReason int
+
+const (
+	EvictExpired EvictReason = iota
+	EvictCapacity
+	EvictManual
+)
+
+type cacheEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+
+	// heapIndex is this entry's position in the Cache's expiry heap,
+	// or -1 if the entry has no TTL and so is not tracked there.
+	heapIndex int
+}
+
+// expiryHeap is a container/heap of cacheEntry pointers ordered by
+// expiresAt, kept independent of the LRU list: LRU order reflects
+// access recency, not expiry, so the two cannot share one structure.
+// It lets the lazy sweep in evictExpiredLocked pop just the entries
+// that have actually expired, in O(log n) per entry, instead of
+// walking the whole cache.
+type expiryHeap[K comparable, V any] []*cacheEntry[K, V]
+
+func (h expiryHeap[K, V]) Len() int { return len(h) }
+
+func (h expiryHeap[K, V]) Less(i, j int) bool {
+	return h[i].expiresAt.Before(h[j].expiresAt)
+}
+
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *expiryHeap[K, V]) Push(x any) {
+	e := x.(*cacheEntry[K, V])
+	e.heapIndex = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.heapIndex = -1
+	*h = old[:n-1]
+	return e
+}
+
+// CacheOptions configures a Cache created via NewCache.
+type CacheOptions[K comparable, V any] struct {
+
+	// MaxEntries is the maximum number of items the cache will hold.
+	// Once reached, the least recently used entry is evicted to make
+	// room for a new one. Zero or negative means unbounded.
+	MaxEntries int
+
+	// DefaultTTL is applied to entries added via Set. Zero means
+	// entries never expire unless added via SetWithTTL.
+	DefaultTTL time.Duration
+
+	// OnEvict, if set, is called whenever an entry leaves the cache,
+	// for any of the EvictReason values.
+	OnEvict func(K, V, EvictReason)
+
+	// Now allows tests to control the notion of "current time". If
+	// nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Cache is a bounded, optionally TTL-based, in-process LRU cache built
+// on top of the same locking discipline as ConcurrentMap. It lets
+// users of the kit avoid pulling in a heavier dependency for simple
+// in-process caching needs. Get/Set are O(1) amortized map+list
+// operations plus an O(log n) expiry-heap update per TTL'd entry -
+// the cache never scans its full contents to find expired items.
+type Cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	opts    CacheOptions[K, V]
+	now     func() time.Time
+	items   map[K]*list.Element
+	order   *list.List // front = most recently used
+	expiry  *expiryHeap[K, V]
+	janitor context.CancelFunc
+}
+
+// NewCache creates a new Cache configured by opts.
+func NewCache[K comparable, V any](opts CacheOptions[K, V]) *Cache[K, V] {
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+	return &Cache[K, V]{
+		opts:   opts,
+		now:    now,
+		items:  make(map[K]*list.Element),
+		order:  list.New(),
+		expiry: &expiryHeap[K, V]{},
+	}
+}
+
+// setExpiryLocked updates e's position in the expiry heap to match a
+// new expiresAt, adding or removing it from the heap as needed.
+func (c *Cache[K, V]) setExpiryLocked(e *cacheEntry[K, V], expiresAt time.Time) {
+	hadExpiry := e.heapIndex >= 0
+	e.expiresAt = expiresAt
+	switch {
+	case hadExpiry && expiresAt.IsZero():
+		heap.Remove(c.expiry, e.heapIndex)
+	case hadExpiry && !expiresAt.IsZero():
+		heap.Fix(c.expiry, e.heapIndex)
+	case !hadExpiry && !expiresAt.IsZero():
+		heap.Push(c.expiry, e)
+	}
+}
+
+func (c *Cache[K, V]) evict(el *list.Element, reason EvictReason) {
+	e := el.Value.(*cacheEntry[K, V])
+	c.order.Remove(el)
+	delete(c.items, e.key)
+	if e.heapIndex >= 0 {
+		heap.Remove(c.expiry, e.heapIndex)
+	}
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(e.key, e.value, reason)
+	}
+}
+
+// evictExpiredLocked pops entries off the expiry heap for as long as
+// its root has already expired, evicting each one. Since the heap is
+// ordered by expiresAt, this does exactly the work needed - O(k log n)
+// for k expired entries - regardless of how large the cache is.
+func (c *Cache[K, V]) evictExpiredLocked() {
+	now := c.now()
+	for c.expiry.Len() > 0 {
+		e := (*c.expiry)[0]
+		if e.expiresAt.After(now) {
+			break
+		}
+		if el, ok := c.items[e.key]; ok {
+			c.evict(el, EvictExpired)
+		} else {
+			heap.Pop(c.expiry)
+		}
+	}
+}
+
+// Get returns the value for k, promoting it to most-recently-used. If
+// the entry is missing or expired, it returns the zero value and false.
+func (c *Cache[K, V]) Get(k K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpiredLocked()
+	el, ok := c.items[k]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry[K, V]).value, true
+}
+
+// Peek returns the value for k without promoting it, and without
+// triggering the lazy expiry sweep beyond checking the entry itself.
+func (c *Cache[K, V]) Peek(k K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[k]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := el.Value.(*cacheEntry[K, V])
+	if !e.expiresAt.IsZero() && !e.expiresAt.After(c.now()) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores v under k using opts.DefaultTTL.
+func (c *Cache[K, V]) Set(k K, v V) {
+	c.SetWithTTL(k, v, c.opts.DefaultTTL)
+}
+
+// SetWithTTL stores v under k with an explicit TTL, overriding
+// opts.DefaultTTL. A zero ttl means the entry never expires on its own.
+func (c *Cache[K, V]) SetWithTTL(k K, v V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpiredLocked()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if el, ok := c.items[k]; ok {
+		e := el.Value.(*cacheEntry[K, V])
+		e.value = v
+		c.setExpiryLocked(e, expiresAt)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&cacheEntry[K, V]{key: k, value: v, heapIndex: -1})
+	c.items[k] = el
+	c.setExpiryLocked(el.Value.(*cacheEntry[K, V]), expiresAt)
+
+	if c.opts.MaxEntries > 0 {
+		for len(c.items) > c.opts.MaxEntries {
+			if back := c.order.Back(); back != nil {
+				c.evict(back, EvictCapacity)
+			} else {
+				break
+			}
+		}
+	}
+}
+
+// Delete removes k from the cache, if present, invoking OnEvict with
+// EvictManual.
+func (c *Cache[K, V]) Delete(k K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[k]; ok {
+		c.evict(el, EvictManual)
+	}
+}
+
+// Len returns the number of entries currently in the cache, including
+// any not-yet-swept expired ones.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Purge removes all entries from the cache, invoking OnEvict with
+// EvictManual for each of them.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		c.evict(el, EvictManual)
+		el = next
+	}
+}
+
+// StartJanitor runs a background goroutine that periodically sweeps
+// expired entries, so that idle keys are reclaimed even without
+// further Get/Set calls. It stops when ctx is canceled, or when
+// StartJanitor is called again, which replaces the running janitor.
+func (c *Cache[K, V]) StartJanitor(ctx context.Context, interval time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	if c.janitor != nil {
+		c.janitor()
+	}
+	c.janitor = cancel
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.mu.Lock()
+				c.evictExpiredLocked()
+				c.mu.Unlock()
+			}
+		}
+	}()
+}
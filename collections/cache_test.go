@@ -0,0 +1,145 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCacheGetSet(t *testing.T) {
+	c := NewCache[string, int](CacheOptions[string, int]{})
+	c.Set("a", 1)
+	v, ok := c.Get("a")
+	if !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v, want 1, true", v, ok)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Fatalf("Get(missing) returned ok=true")
+	}
+}
+
+func TestCacheCapacityEvictsLRU(t *testing.T) {
+	var evicted []string
+	c := NewCache[string, int](CacheOptions[string, int]{
+		MaxEntries: 2,
+		OnEvict: func(k string, v int, reason EvictReason) {
+			if reason != EvictCapacity {
+				t.Fatalf("unexpected evict reason %v for key %q", reason, k)
+			}
+			evicted = append(evicted, k)
+		},
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // promote a, so b becomes LRU
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Fatalf("evicted = %v, want [b]", evicted)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestCacheTTLExpiry(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := NewCache[string, int](CacheOptions[string, int]{
+		Now: func() time.Time { return now },
+	})
+	c.SetWithTTL("a", 1, time.Second)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected a to still be present before expiry")
+	}
+
+	now = now.Add(2 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a to be expired")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0 after expiry sweep", c.Len())
+	}
+}
+
+func TestCachePeekDoesNotPromote(t *testing.T) {
+	c := NewCache[string, int](CacheOptions[string, int]{MaxEntries: 2})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Peek("a") // should NOT promote a
+	c.Set("c", 3)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a to be evicted since Peek must not promote it")
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Fatalf("expected b to survive")
+	}
+}
+
+func TestCacheRenewingTTLUpdatesExpiry(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := NewCache[string, int](CacheOptions[string, int]{
+		Now: func() time.Time { return now },
+	})
+	c.SetWithTTL("a", 1, time.Second)
+	c.SetWithTTL("a", 2, 10*time.Second)
+
+	now = now.Add(2 * time.Second)
+	v, ok := c.Get("a")
+	if !ok || v != 2 {
+		t.Fatalf("Get(a) = %v, %v, want 2, true after TTL renewal", v, ok)
+	}
+}
+
+func TestCacheExpiryEvictsOnlyExpiredEntries(t *testing.T) {
+	const n = 50_000
+	now := time.Unix(0, 0)
+	c := NewCache[string, int](CacheOptions[string, int]{
+		Now: func() time.Time { return now },
+	})
+	for i := 0; i < n; i++ {
+		c.Set(strconv.Itoa(i), i) // no TTL -> never enters the expiry heap
+	}
+	c.SetWithTTL("expiring", -1, time.Nanosecond)
+	now = now.Add(time.Second)
+
+	if _, ok := c.Get("expiring"); ok {
+		t.Fatalf("expected expiring entry to be gone")
+	}
+	if c.Len() != n {
+		t.Fatalf("Len() = %d, want %d - only the TTL'd entry should have been evicted", c.Len(), n)
+	}
+}
+
+func BenchmarkCacheGetWithLargeNonExpiringSet(b *testing.B) {
+	const n = 100_000
+	c := NewCache[string, int](CacheOptions[string, int]{})
+	keys := make([]string, n)
+	for i := 0; i < n; i++ {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		c.Set(keys[i], i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(keys[i%n])
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"hash/maphash"
+	"sync"
+)
+
+// DefaultShardCount is used by NewShardedConcurrentMap when the caller
+// does not have a more specific number of shards in mind.
+const DefaultShardCount = 32
+
+var shardSeed = maphash.MakeSeed()
+
+// Hasher produces a stable 64bit hash for a key of type K. It is used
+// by ShardedConcurrentMap to route a key to one of its shards.
+type Hasher[K comparable] func(k K) uint64
+
+// defaultHasher provides fast hashing for the key types commonly used
+// across the CNC toolkit (strings and integers) and falls back to a
+// reflection-based hash (via fmt.Fprintf) for anything else.
+func defaultHasher[K comparable]() Hasher[K] {
+	return func(k K) uint64 {
+		switch v := any(k).(type) {
+		case string:
+			var h maphash.Hash
+			h.SetSeed(shardSeed)
+			h.WriteString(v)
+			return h.Sum64()
+		case int:
+			return hashUint64(uint64(v))
+		case int8:
+			return hashUint64(uint64(v))
+		case int16:
+			return hashUint64(uint64(v))
+		case int32:
+			return hashUint64(uint64(v))
+		case int64:
+			return hashUint64(uint64(v))
+		case uint:
+			return hashUint64(uint64(v))
+		case uint8:
+			return hashUint64(uint64(v))
+		case uint16:
+			return hashUint64(uint64(v))
+		case uint32:
+			return hashUint64(uint64(v))
+		case uint64:
+			return hashUint64(v)
+		default:
+			var h maphash.Hash
+			h.SetSeed(shardSeed)
+			fmt.Fprintf(&h, "%v", k)
+			return h.Sum64()
+		}
+	}
+}
+
+func hashUint64(v uint64) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+	return h.Sum64()
+}
+
+type cmapShard[K comparable, T any] struct {
+	sync.RWMutex
+	data map[K]T
+}
+
+// ShardedConcurrentMap is a drop-in alternative to ConcurrentMap for
+// workloads where a single sync.RWMutex becomes a bottleneck (e.g. many
+// goroutines writing to unrelated keys at once). Keys are routed to one
+// of N independently-locked shards via a Hasher, so writers touching
+// different shards no longer serialize on each other.
+type ShardedConcurrentMap[K comparable, T any] struct {
+	shards []*cmapShard[K, T]
+	hasher Hasher[K]
+}
+
+func (cm *ShardedConcurrentMap[K, T]) shardFor(k K) *cmapShard[K, T] {
+	idx := cm.hasher(k) % uint64(len(cm.shards))
+	return cm.shards[idx]
+}
+
+func (cm *ShardedConcurrentMap[K, T]) Get(k K) T {
+	sh := cm.shardFor(k)
+	sh.RLock()
+	defer sh.RUnlock()
+	return sh.data[k]
+}
+
+func (cm *ShardedConcurrentMap[K, T]) GetWithTest(k K) (T, bool) {
+	sh := cm.shardFor(k)
+	sh.RLock()
+	defer sh.RUnlock()
+	v, ok := sh.data[k]
+	return v, ok
+}
+
+func (cm *ShardedConcurrentMap[K, T]) HasKey(k K) bool {
+	sh := cm.shardFor(k)
+	sh.RLock()
+	defer sh.RUnlock()
+	_, ok := sh.data[k]
+	return ok
+}
+
+func (cm *ShardedConcurrentMap[K, T]) Set(k K, v T) {
+	sh := cm.shardFor(k)
+	sh.Lock()
+	defer sh.Unlock()
+	sh.data[k] = v
+}
+
+func (cm *ShardedConcurrentMap[K, T]) Delete(k K) {
+	sh := cm.shardFor(k)
+	sh.Lock()
+	defer sh.Unlock()
+	delete(sh.data, k)
+}
+
+// ForEach iterates through all the items, shard by shard. As with
+// ConcurrentMap.ForEach, each shard's keys are snapshotted first and
+// the corresponding value is then looked up (and may or may not still
+// be present) for each yield call, to keep the method deadlock-resistant.
+func (cm *ShardedConcurrentMap[K, T]) ForEach(yield func(k K, v T, ok bool)) {
+	for _, sh := range cm.shards {
+		var keys []K
+		sh.RLock()
+		keys = make([]K, len(sh.data))
+		var i int
+		for k := range sh.data {
+			keys[i] = k
+			i++
+		}
+		sh.RUnlock()
+		for _, k := range keys {
+			sh.RLock()
+			v, ok := sh.data[k]
+			sh.RUnlock()
+			yield(k, v, ok)
+		}
+	}
+}
+
+func (cm *ShardedConcurrentMap[K, T]) Update(fn func(k K, v T) T) {
+	for _, sh := range cm.shards {
+		sh.Lock()
+		for k, v := range sh.data {
+			sh.data[k] = fn(k, v)
+		}
+		sh.Unlock()
+	}
+}
+
+func (cm *ShardedConcurrentMap[K, T]) Keys() []K {
+	var ans []K
+	for _, sh := range cm.shards {
+		sh.RLock()
+		for k := range sh.data {
+			ans = append(ans, k)
+		}
+		sh.RUnlock()
+	}
+	return ans
+}
+
+func (cm *ShardedConcurrentMap[K, T]) Values() []T {
+	var ans []T
+	for _, sh := range cm.shards {
+		sh.RLock()
+		for _, v := range sh.data {
+			ans = append(ans, v)
+		}
+		sh.RUnlock()
+	}
+	return ans
+}
+
+// AsMap creates a shallow copy of all the key-value pairs held across
+// all the shards.
+func (cm *ShardedConcurrentMap[K, T]) AsMap() map[K]T {
+	ans := make(map[K]T)
+	for _, sh := range cm.shards {
+		sh.RLock()
+		for k, v := range sh.data {
+			ans[k] = v
+		}
+		sh.RUnlock()
+	}
+	return ans
+}
+
+// Len returns the number of key-value pairs stored across all shards.
+func (cm *ShardedConcurrentMap[K, T]) Len() int {
+	var total int
+	for _, sh := range cm.shards {
+		sh.RLock()
+		total += len(sh.data)
+		sh.RUnlock()
+	}
+	return total
+}
+
+func (cm *ShardedConcurrentMap[K, T]) Filter(fn func(k K, v T) bool) *ShardedConcurrentMap[K, T] {
+	ans := NewShardedConcurrentMapWithHasher[K, T](len(cm.shards), cm.hasher)
+	for _, sh := range cm.shards {
+		sh.RLock()
+		for kx, vx := range sh.data {
+			if fn(kx, vx) {
+				ans.Set(kx, vx)
+			}
+		}
+		sh.RUnlock()
+	}
+	return ans
+}
+
+func (cm *ShardedConcurrentMap[K, T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cm.AsMap())
+}
+
+// NewShardedConcurrentMap creates a ShardedConcurrentMap with the given
+// number of shards, using the default hasher (fast paths for strings
+// and integers, reflection-based fallback otherwise). If shards <= 0,
+// DefaultShardCount is used.
+func NewShardedConcurrentMap[K comparable, T any](shards int) *ShardedConcurrentMap[K, T] {
+	return NewShardedConcurrentMapWithHasher[K, T](shards, defaultHasher[K]())
+}
+
+// NewShardedConcurrentMapWithHasher is like NewShardedConcurrentMap but
+// lets the caller supply a custom Hasher, e.g. when K's default hashing
+// would collide too often for a particular workload.
+func NewShardedConcurrentMapWithHasher[K comparable, T any](shards int, hasher Hasher[K]) *ShardedConcurrentMap[K, T] {
+	if shards <= 0 {
+		shards = DefaultShardCount
+	}
+	ans := &ShardedConcurrentMap[K, T]{
+		shards: make([]*cmapShard[K, T], shards),
+		hasher: hasher,
+	}
+	for i := range ans.shards {
+		ans.shards[i] = &cmapShard[K, T]{data: make(map[K]T)}
+	}
+	return ans
+}
+
+// NewShardedConcurrentMapFromJSON decodes data into a ShardedConcurrentMap
+// with the given number of shards (DefaultShardCount if shards <= 0).
+func NewShardedConcurrentMapFromJSON[K comparable, T any](data []byte, shards int) (*ShardedConcurrentMap[K, T], error) {
+	data2 := make(map[K]T)
+	if err := json.Unmarshal(data, &data2); err != nil {
+		return nil, err
+	}
+	ans := NewShardedConcurrentMap[K, T](shards)
+	for k, v := range data2 {
+		ans.Set(k, v)
+	}
+	return ans, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+const benchKeySpace = 10000
+
+func BenchmarkConcurrentMapContended(b *testing.B) {
+	cm := NewConcurrentMap[string, int]()
+	for i := 0; i < benchKeySpace; i++ {
+		cm.Set(strconv.Itoa(i), i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			k := strconv.Itoa(i % benchKeySpace)
+			if i%10 == 0 {
+				cm.Set(k, i)
+			} else {
+				cm.Get(k)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkShardedConcurrentMapContended(b *testing.B) {
+	for _, shards := range []int{8, 16, 32, 64} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			cm := NewShardedConcurrentMap[string, int](shards)
+			for i := 0; i < benchKeySpace; i++ {
+				cm.Set(strconv.Itoa(i), i)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				var i int
+				for pb.Next() {
+					k := strconv.Itoa(i % benchKeySpace)
+					if i%10 == 0 {
+						cm.Set(k, i)
+					} else {
+						cm.Get(k)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
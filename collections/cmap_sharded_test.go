@@ -0,0 +1,182 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestShardedConcurrentMapBasicOps(t *testing.T) {
+	cm := NewShardedConcurrentMap[string, int](8)
+	cm.Set("a", 1)
+	cm.Set("b", 2)
+
+	if v := cm.Get("a"); v != 1 {
+		t.Fatalf("Get(a) = %d, want 1", v)
+	}
+	if !cm.HasKey("b") {
+		t.Fatalf("HasKey(b) = false, want true")
+	}
+	if cm.HasKey("missing") {
+		t.Fatalf("HasKey(missing) = true, want false")
+	}
+	if cm.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", cm.Len())
+	}
+
+	cm.Delete("a")
+	if cm.HasKey("a") {
+		t.Fatalf("HasKey(a) = true after Delete")
+	}
+	if cm.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1 after Delete", cm.Len())
+	}
+}
+
+// TestShardedConcurrentMapStableShardRouting checks that the same key
+// always resolves to the same shard across repeated lookups - the
+// property the whole sharding scheme depends on.
+func TestShardedConcurrentMapStableShardRouting(t *testing.T) {
+	cm := NewShardedConcurrentMap[string, int](16)
+	keys := make([]string, 200)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		cm.Set(keys[i], i)
+	}
+	for _, k := range keys {
+		want := cm.shardFor(k)
+		for i := 0; i < 10; i++ {
+			if got := cm.shardFor(k); got != want {
+				t.Fatalf("shardFor(%q) is not stable across calls", k)
+			}
+		}
+	}
+}
+
+// TestShardedConcurrentMapFallbackHasherDistributes exercises the
+// fmt.Fprintf-based fallback hasher (used for non-string/int key
+// types) with a struct key, and checks it actually spreads keys
+// across shards instead of collapsing them all onto one.
+func TestShardedConcurrentMapFallbackHasherDistributes(t *testing.T) {
+	type compositeKey struct {
+		A string
+		B int
+	}
+	const shardCount = 16
+	cm := NewShardedConcurrentMap[compositeKey, int](shardCount)
+
+	seenShards := make(map[*cmapShard[compositeKey, int]]bool)
+	for i := 0; i < 500; i++ {
+		k := compositeKey{A: "k", B: i}
+		cm.Set(k, i)
+		seenShards[cm.shardFor(k)] = true
+	}
+
+	if len(seenShards) < shardCount/2 {
+		t.Fatalf("fallback hasher only used %d/%d shards for 500 distinct keys - poor distribution", len(seenShards), shardCount)
+	}
+	if cm.Len() != 500 {
+		t.Fatalf("Len() = %d, want 500", cm.Len())
+	}
+}
+
+func TestShardedConcurrentMapFilter(t *testing.T) {
+	cm := NewShardedConcurrentMap[int, int](4)
+	for i := 0; i < 10; i++ {
+		cm.Set(i, i*i)
+	}
+	even := cm.Filter(func(k, v int) bool { return k%2 == 0 })
+	if even.Len() != 5 {
+		t.Fatalf("Filter result Len() = %d, want 5", even.Len())
+	}
+	for k, v := range even.AsMap() {
+		if k%2 != 0 || v != k*k {
+			t.Fatalf("Filter kept unexpected pair %d -> %d", k, v)
+		}
+	}
+}
+
+func TestShardedConcurrentMapMarshalJSONRoundTrip(t *testing.T) {
+	cm := NewShardedConcurrentMap[string, int](4)
+	cm.Set("a", 1)
+	cm.Set("b", 2)
+	cm.Set("c", 3)
+
+	data, err := json.Marshal(cm)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	restored, err := NewShardedConcurrentMapFromJSON[string, int](data, 4)
+	if err != nil {
+		t.Fatalf("NewShardedConcurrentMapFromJSON failed: %v", err)
+	}
+	if restored.Len() != cm.Len() {
+		t.Fatalf("restored Len() = %d, want %d", restored.Len(), cm.Len())
+	}
+	for k, v := range cm.AsMap() {
+		got, ok := restored.GetWithTest(k)
+		if !ok || got != v {
+			t.Fatalf("restored[%q] = %d, %v, want %d, true", k, got, ok, v)
+		}
+	}
+}
+
+func TestShardedConcurrentMapForEachAndUpdate(t *testing.T) {
+	cm := NewShardedConcurrentMap[int, int](4)
+	for i := 0; i < 5; i++ {
+		cm.Set(i, i)
+	}
+	cm.Update(func(k, v int) int { return v + 100 })
+
+	seen := make(map[int]int)
+	cm.ForEach(func(k, v int, ok bool) {
+		if ok {
+			seen[k] = v
+		}
+	})
+	if len(seen) != 5 {
+		t.Fatalf("ForEach visited %d keys, want 5", len(seen))
+	}
+	for k, v := range seen {
+		if v != k+100 {
+			t.Fatalf("seen[%d] = %d, want %d", k, v, k+100)
+		}
+	}
+}
+
+func TestShardedConcurrentMapDefaultShardCount(t *testing.T) {
+	cm := NewShardedConcurrentMap[string, int](0)
+	if len(cm.shards) != DefaultShardCount {
+		t.Fatalf("shard count = %d, want DefaultShardCount (%d)", len(cm.shards), DefaultShardCount)
+	}
+}
+
+func TestShardedConcurrentMapIntKeys(t *testing.T) {
+	cm := NewShardedConcurrentMap[int, string](8)
+	for i := 0; i < 100; i++ {
+		cm.Set(i, strconv.Itoa(i))
+	}
+	for i := 0; i < 100; i++ {
+		v, ok := cm.GetWithTest(i)
+		if !ok || v != strconv.Itoa(i) {
+			t.Fatalf("GetWithTest(%d) = %q, %v, want %q, true", i, v, ok, strconv.Itoa(i))
+		}
+	}
+}
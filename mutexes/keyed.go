@@ -0,0 +1,137 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mutexes provides locking primitives that complement
+// collections.ConcurrentMap for read-modify-write patterns where a
+// single global mutex would serialize unrelated keys.
+package mutexes
+
+import (
+	"fmt"
+	"hash/maphash"
+	"sync"
+)
+
+const numShards = 16
+
+var keyedMutexSeed = maphash.MakeSeed()
+
+type keyedMutexEntry struct {
+	mu   sync.RWMutex
+	refs int
+}
+
+type keyedMutexShard[K comparable] struct {
+	sync.Mutex
+	entries map[K]*keyedMutexEntry
+}
+
+// KeyedMutex provides per-key locking: callers serialize on the same
+// logical resource (e.g. a corpus name or a user id) without blocking
+// on unrelated keys, and without having to keep a map of mutexes alive
+// forever - idle keys are removed once their last holder unlocks.
+type KeyedMutex[K comparable] struct {
+	shards [numShards]*keyedMutexShard[K]
+	hash   func(k K) uint64
+}
+
+// NewKeyedMutex creates a KeyedMutex for keys of type K.
+func NewKeyedMutex[K comparable]() *KeyedMutex[K] {
+	ans := &KeyedMutex[K]{hash: keyHash[K]}
+	for i := range ans.shards {
+		ans.shards[i] = &keyedMutexShard[K]{entries: make(map[K]*keyedMutexEntry)}
+	}
+	return ans
+}
+
+// keyHash hashes a key into a shard index. Strings are written
+// directly; any other comparable type is routed through fmt.Fprintf,
+// which is fine here since keys only need to land on a stable shard,
+// not to be hashed with cryptographic-quality distribution.
+func keyHash[K comparable](k K) uint64 {
+	var h maphash.Hash
+	h.SetSeed(keyedMutexSeed)
+	if s, ok := any(k).(string); ok {
+		h.WriteString(s)
+	} else {
+		fmt.Fprintf(&h, "%v", k)
+	}
+	return h.Sum64()
+}
+
+func (km *KeyedMutex[K]) shardFor(k K) *keyedMutexShard[K] {
+	return km.shards[km.hash(k)%numShards]
+}
+
+func (km *KeyedMutex[K]) acquire(k K) *keyedMutexEntry {
+	sh := km.shardFor(k)
+	sh.Lock()
+	e, ok := sh.entries[k]
+	if !ok {
+		e = &keyedMutexEntry{}
+		sh.entries[k] = e
+	}
+	e.refs++
+	sh.Unlock()
+	return e
+}
+
+func (km *KeyedMutex[K]) release(k K, e *keyedMutexEntry) {
+	sh := km.shardFor(k)
+	sh.Lock()
+	e.refs--
+	if e.refs == 0 {
+		delete(sh.entries, k)
+	}
+	sh.Unlock()
+}
+
+// Lock acquires an exclusive lock for k and returns a function to
+// release it. The caller must call the returned function exactly once.
+func (km *KeyedMutex[K]) Lock(k K) func() {
+	e := km.acquire(k)
+	e.mu.Lock()
+	return func() {
+		e.mu.Unlock()
+		km.release(k, e)
+	}
+}
+
+// RLock acquires a shared lock for k and returns a function to release
+// it. The caller must call the returned function exactly once.
+func (km *KeyedMutex[K]) RLock(k K) func() {
+	e := km.acquire(k)
+	e.mu.RLock()
+	return func() {
+		e.mu.RUnlock()
+		km.release(k, e)
+	}
+}
+
+// TryLock attempts to acquire an exclusive lock for k without blocking.
+// On success it returns a release function and ok == true; the caller
+// must call the returned function exactly once. On failure it returns
+// ok == false and a nil function.
+func (km *KeyedMutex[K]) TryLock(k K) (unlock func(), ok bool) {
+	e := km.acquire(k)
+	if !e.mu.TryLock() {
+		km.release(k, e)
+		return nil, false
+	}
+	return func() {
+		e.mu.Unlock()
+		km.release(k, e)
+	}, true
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutexes
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// entryCount returns the total number of entries still tracked across
+// all shards, which should always fall back to zero once every
+// acquired lock for a key has been released.
+func (km *KeyedMutex[K]) entryCount() int {
+	var n int
+	for _, sh := range km.shards {
+		sh.Lock()
+		n += len(sh.entries)
+		sh.Unlock()
+	}
+	return n
+}
+
+func TestKeyedMutexMutualExclusion(t *testing.T) {
+	km := NewKeyedMutex[string]()
+	var counter int
+	var wg sync.WaitGroup
+	const goroutines = 100
+	const incrPerGoroutine = 100
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < incrPerGoroutine; j++ {
+				unlock := km.Lock("shared-key")
+				counter++
+				unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if want := goroutines * incrPerGoroutine; counter != want {
+		t.Fatalf("counter = %d, want %d (a race would corrupt this)", counter, want)
+	}
+}
+
+func TestKeyedMutexDifferentKeysDoNotBlockEachOther(t *testing.T) {
+	km := NewKeyedMutex[string]()
+	unlockA := km.Lock("a")
+	defer unlockA()
+
+	done := make(chan struct{})
+	go func() {
+		unlockB := km.Lock("b")
+		unlockB()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("lock on a different key should not block")
+	}
+}
+
+func TestKeyedMutexTryLock(t *testing.T) {
+	km := NewKeyedMutex[string]()
+	unlock := km.Lock("k")
+
+	if _, ok := km.TryLock("k"); ok {
+		t.Fatalf("TryLock succeeded while key was already locked")
+	}
+
+	unlock()
+
+	unlock2, ok := km.TryLock("k")
+	if !ok {
+		t.Fatalf("TryLock failed after the key was unlocked")
+	}
+	unlock2()
+}
+
+func TestKeyedMutexRLockAllowsConcurrentReaders(t *testing.T) {
+	km := NewKeyedMutex[string]()
+	unlock1 := km.RLock("k")
+	unlock2 := km.RLock("k")
+	unlock1()
+	unlock2()
+}
+
+func TestKeyedMutexDoesNotLeakEntries(t *testing.T) {
+	km := NewKeyedMutex[string]()
+	var wg sync.WaitGroup
+	const keys = 50
+	const goroutinesPerKey = 20
+
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		for j := 0; j < goroutinesPerKey; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock := km.Lock(key)
+				unlock()
+			}()
+		}
+	}
+	wg.Wait()
+
+	if n := km.entryCount(); n != 0 {
+		t.Fatalf("entryCount() = %d, want 0 - idle keys should be reclaimed", n)
+	}
+}
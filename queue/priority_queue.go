@@ -0,0 +1,151 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+type pqHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *pqHeap[T]) Len() int           { return len(h.items) }
+func (h *pqHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *pqHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *pqHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+func (h *pqHeap[T]) Pop() any {
+	old := h.items
+	n := len(old)
+	v := old[n-1]
+	var zero T
+	old[n-1] = zero
+	h.items = old[:n-1]
+	return v
+}
+
+// PriorityQueue is a generic, concurrency-safe priority queue backed by
+// container/heap. Less(a, b) reports whether a has higher priority
+// than b, i.e. should be popped first.
+type PriorityQueue[T any] struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	h           *pqHeap[T]
+	maxCapacity int
+}
+
+// NewPriorityQueue creates a PriorityQueue ordered by less. A
+// maxCapacity <= 0 means unbounded.
+func NewPriorityQueue[T any](less func(a, b T) bool, maxCapacity int) *PriorityQueue[T] {
+	q := &PriorityQueue[T]{
+		h:           &pqHeap[T]{less: less},
+		maxCapacity: maxCapacity,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Push adds v to the queue. It returns ErrFull if the queue is bounded
+// and already full.
+func (q *PriorityQueue[T]) Push(v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.maxCapacity > 0 && q.h.Len() >= q.maxCapacity {
+		return ErrFull
+	}
+	heap.Push(q.h, v)
+	q.cond.Signal()
+	return nil
+}
+
+// Pop removes and returns the highest-priority item. The second return
+// value is false if the queue was empty.
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.popLocked()
+}
+
+func (q *PriorityQueue[T]) popLocked() (T, bool) {
+	if q.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(q.h).(T), true
+}
+
+// PopWait blocks until an item is available or ctx is done.
+func (q *PriorityQueue[T]) PopWait(ctx context.Context) (T, error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			q.mu.Lock()
+			q.cond.Broadcast()
+			q.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.h.Len() == 0 {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, err
+		}
+		q.cond.Wait()
+	}
+	v, _ := q.popLocked()
+	return v, nil
+}
+
+// Len returns the number of items currently queued.
+func (q *PriorityQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.h.Len()
+}
+
+// Drain removes and returns all currently queued items, in priority
+// order (highest priority first).
+func (q *PriorityQueue[T]) Drain() []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	ans := make([]T, 0, q.h.Len())
+	for q.h.Len() > 0 {
+		v, _ := q.popLocked()
+		ans = append(ans, v)
+	}
+	return ans
+}
+
+// Range calls fn for each queued item in no particular order (the
+// underlying heap is not sorted), stopping early if fn returns false.
+// It does not remove any items.
+func (q *PriorityQueue[T]) Range(fn func(T) bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, v := range q.h.items {
+		if !fn(v) {
+			return
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package queue provides generic FIFO and priority queues with both
+// non-blocking and blocking APIs, for building worker-pool patterns on
+// top of the CNC toolkit.
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrFull is returned by Push when the queue has a MaxCapacity and is
+// currently full.
+var ErrFull = errors.New("queue is full")
+
+// Queue is a generic, concurrency-safe FIFO queue backed by a ring
+// buffer that grows as needed, up to an optional MaxCapacity.
+type Queue[T any] struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	buf         []T
+	head        int
+	size        int
+	maxCapacity int
+}
+
+// NewQueue creates a Queue. A maxCapacity <= 0 means unbounded.
+func NewQueue[T any](maxCapacity int) *Queue[T] {
+	q := &Queue[T]{
+		buf:         make([]T, 8),
+		maxCapacity: maxCapacity,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *Queue[T]) growLocked() {
+	newBuf := make([]T, len(q.buf)*2)
+	for i := 0; i < q.size; i++ {
+		newBuf[i] = q.buf[(q.head+i)%len(q.buf)]
+	}
+	q.buf = newBuf
+	q.head = 0
+}
+
+// Push adds v to the back of the queue. It returns ErrFull if the
+// queue is bounded (MaxCapacity > 0) and already full.
+func (q *Queue[T]) Push(v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.maxCapacity > 0 && q.size >= q.maxCapacity {
+		return ErrFull
+	}
+	if q.size == len(q.buf) {
+		q.growLocked()
+	}
+	q.buf[(q.head+q.size)%len(q.buf)] = v
+	q.size++
+	q.cond.Signal()
+	return nil
+}
+
+// Pop removes and returns the item at the front of the queue. The
+// second return value is false if the queue was empty.
+func (q *Queue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.popLocked()
+}
+
+func (q *Queue[T]) popLocked() (T, bool) {
+	if q.size == 0 {
+		var zero T
+		return zero, false
+	}
+	v := q.buf[q.head]
+	var zero T
+	q.buf[q.head] = zero
+	q.head = (q.head + 1) % len(q.buf)
+	q.size--
+	return v, true
+}
+
+// PopWait blocks until an item is available or ctx is done. It
+// returns ctx.Err() if ctx is done before an item arrives.
+func (q *Queue[T]) PopWait(ctx context.Context) (T, error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			q.mu.Lock()
+			q.cond.Broadcast()
+			q.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.size == 0 {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, err
+		}
+		q.cond.Wait()
+	}
+	v, _ := q.popLocked()
+	return v, nil
+}
+
+// Len returns the number of items currently queued.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// Drain removes and returns all currently queued items, in FIFO order.
+func (q *Queue[T]) Drain() []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	ans := make([]T, q.size)
+	for i := 0; i < q.size; i++ {
+		ans[i] = q.buf[(q.head+i)%len(q.buf)]
+	}
+	q.head = 0
+	q.size = 0
+	return ans
+}
+
+// Range calls fn for each queued item, front to back, stopping early
+// if fn returns false. It does not remove any items.
+func (q *Queue[T]) Range(fn func(T) bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := 0; i < q.size; i++ {
+		if !fn(q.buf[(q.head+i)%len(q.buf)]) {
+			return
+		}
+	}
+}
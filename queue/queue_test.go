@@ -0,0 +1,238 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueuePushPopFIFO(t *testing.T) {
+	q := NewQueue[int](0)
+	for i := 0; i < 5; i++ {
+		if err := q.Push(i); err != nil {
+			t.Fatalf("Push(%d) failed: %v", i, err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		v, ok := q.Pop()
+		if !ok || v != i {
+			t.Fatalf("Pop() = %d, %v, want %d, true", v, ok, i)
+		}
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatalf("Pop() on empty queue returned ok=true")
+	}
+}
+
+func TestQueuePushErrFullWhenBounded(t *testing.T) {
+	q := NewQueue[int](2)
+	if err := q.Push(1); err != nil {
+		t.Fatalf("Push(1) failed: %v", err)
+	}
+	if err := q.Push(2); err != nil {
+		t.Fatalf("Push(2) failed: %v", err)
+	}
+	if err := q.Push(3); !errors.Is(err, ErrFull) {
+		t.Fatalf("Push(3) err = %v, want ErrFull", err)
+	}
+}
+
+// TestQueueRingBufferWraparound pushes and pops past the initial
+// buffer capacity without ever draining it fully, forcing the write
+// position to wrap around the underlying ring, and forces a grow
+// while head is non-zero.
+func TestQueueRingBufferWraparound(t *testing.T) {
+	q := NewQueue[int](0)
+	next := 0
+	push := func(n int) {
+		for i := 0; i < n; i++ {
+			if err := q.Push(next); err != nil {
+				t.Fatalf("Push(%d) failed: %v", next, err)
+			}
+			next++
+		}
+	}
+	popExpect := func(n int, want []int) {
+		for i := 0; i < n; i++ {
+			v, ok := q.Pop()
+			if !ok || v != want[i] {
+				t.Fatalf("Pop() = %d, %v, want %d, true", v, ok, want[i])
+			}
+		}
+	}
+
+	push(6)                         // buf len 8, size 6, head 0
+	popExpect(4, []int{0, 1, 2, 3}) // head now 4, size 2 (values 4,5 remain)
+	push(10)                        // forces at least one grow while head != 0
+
+	remaining := make([]int, 0, 12)
+	for {
+		v, ok := q.Pop()
+		if !ok {
+			break
+		}
+		remaining = append(remaining, v)
+	}
+	want := []int{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	if len(remaining) != len(want) {
+		t.Fatalf("drained %v items, want %v", remaining, want)
+	}
+	for i := range want {
+		if remaining[i] != want[i] {
+			t.Fatalf("drained order = %v, want %v", remaining, want)
+		}
+	}
+}
+
+func TestQueuePopWaitBlocksThenUnblocksOnPush(t *testing.T) {
+	q := NewQueue[int](0)
+	type result struct {
+		v   int
+		err error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		v, err := q.PopWait(context.Background())
+		resCh <- result{v, err}
+	}()
+
+	select {
+	case <-resCh:
+		t.Fatalf("PopWait returned before anything was pushed")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := q.Push(42); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	select {
+	case r := <-resCh:
+		if r.err != nil || r.v != 42 {
+			t.Fatalf("PopWait() = %v, %v, want 42, nil", r.v, r.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("PopWait did not unblock after Push")
+	}
+}
+
+func TestQueuePopWaitCancellation(t *testing.T) {
+	q := NewQueue[int](0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := q.PopWait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PopWait() err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestQueueDrainAndRangeOrder(t *testing.T) {
+	q := NewQueue[int](0)
+	for i := 0; i < 5; i++ {
+		q.Push(i)
+	}
+
+	var seen []int
+	q.Range(func(v int) bool {
+		seen = append(seen, v)
+		return true
+	})
+	for i, v := range seen {
+		if v != i {
+			t.Fatalf("Range() order = %v, want 0..4", seen)
+		}
+	}
+	if q.Len() != 5 {
+		t.Fatalf("Range should not remove items, Len() = %d, want 5", q.Len())
+	}
+
+	var stopped []int
+	q.Range(func(v int) bool {
+		stopped = append(stopped, v)
+		return v < 2
+	})
+	if len(stopped) != 3 {
+		t.Fatalf("Range() should stop early, got %v", stopped)
+	}
+
+	drained := q.Drain()
+	for i, v := range drained {
+		if v != i {
+			t.Fatalf("Drain() order = %v, want 0..4", drained)
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatalf("Len() after Drain() = %d, want 0", q.Len())
+	}
+}
+
+func TestPriorityQueueOrdering(t *testing.T) {
+	pq := NewPriorityQueue[int](func(a, b int) bool { return a < b }, 0)
+	for _, v := range []int{5, 1, 9, 3, 7} {
+		if err := pq.Push(v); err != nil {
+			t.Fatalf("Push(%d) failed: %v", v, err)
+		}
+	}
+	want := []int{1, 3, 5, 7, 9}
+	for _, w := range want {
+		v, ok := pq.Pop()
+		if !ok || v != w {
+			t.Fatalf("Pop() = %d, %v, want %d, true", v, ok, w)
+		}
+	}
+}
+
+func TestPriorityQueueErrFullWhenBounded(t *testing.T) {
+	pq := NewPriorityQueue[int](func(a, b int) bool { return a < b }, 1)
+	if err := pq.Push(1); err != nil {
+		t.Fatalf("Push(1) failed: %v", err)
+	}
+	if err := pq.Push(2); !errors.Is(err, ErrFull) {
+		t.Fatalf("Push(2) err = %v, want ErrFull", err)
+	}
+}
+
+func TestPriorityQueuePopWaitCancellation(t *testing.T) {
+	pq := NewPriorityQueue[int](func(a, b int) bool { return a < b }, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := pq.PopWait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PopWait() err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestPriorityQueueDrainOrder(t *testing.T) {
+	pq := NewPriorityQueue[int](func(a, b int) bool { return a < b }, 0)
+	for _, v := range []int{5, 1, 9, 3, 7} {
+		pq.Push(v)
+	}
+	drained := pq.Drain()
+	want := []int{1, 3, 5, 7, 9}
+	for i := range want {
+		if drained[i] != want[i] {
+			t.Fatalf("Drain() = %v, want %v", drained, want)
+		}
+	}
+	if pq.Len() != 0 {
+		t.Fatalf("Len() after Drain() = %d, want 0", pq.Len())
+	}
+}
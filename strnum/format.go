@@ -0,0 +1,88 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strnum
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatWithThousands formats n with sep inserted as a thousands
+// separator (e.g. 1234567 with sep '.' becomes "1.234.567").
+func FormatWithThousands(n int64, sep rune) string {
+	s := strconv.FormatInt(n, 10)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	groups := []string{}
+	for len(s) > 3 {
+		groups = append([]string{s[len(s)-3:]}, groups...)
+		s = s[:len(s)-3]
+	}
+	groups = append([]string{s}, groups...)
+	ans := strings.Join(groups, string(sep))
+	if neg {
+		ans = "-" + ans
+	}
+	return ans
+}
+
+var humanSizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+}
+
+// FormatHumanSize formats a byte count using binary (KiB/MiB/GiB)
+// units, picking the largest unit for which n >= 1 of that unit.
+func FormatHumanSize(n int64) string {
+	for _, u := range humanSizeUnits {
+		if n >= u.factor {
+			return fmt.Sprintf("%.2f %s", float64(n)/float64(u.factor), u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// ParseHumanSize is the inverse of FormatHumanSize up to the
+// two-decimal rounding FormatHumanSize applies. It accepts an
+// optional "B", "KiB", "MiB" or "GiB" suffix (case-sensitive,
+// whitespace before the suffix is ignored); a bare number is
+// interpreted as bytes.
+func ParseHumanSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	for _, u := range humanSizeUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			numPart := strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			v, err := strconv.ParseFloat(numPart, 64)
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse human size %q: %w", s, err)
+			}
+			return int64(v * float64(u.factor)), nil
+		}
+	}
+	numPart := strings.TrimSpace(strings.TrimSuffix(s, "B"))
+	v, err := strconv.ParseFloat(numPart, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse human size %q: %w", s, err)
+	}
+	return int64(v), nil
+}
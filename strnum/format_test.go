@@ -0,0 +1,115 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strnum
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatWithThousands(t *testing.T) {
+	tests := []struct {
+		name string
+		in   int64
+		sep  rune
+		exp  string
+	}{
+		{"zero", 0, ',', "0"},
+		{"small", 123, ',', "123"},
+		{"exact group", 1000, ',', "1,000"},
+		{"multi group", 1234567, ',', "1,234,567"},
+		{"negative", -1234567, ',', "-1,234,567"},
+		{"negative small", -42, ',', "-42"},
+		{"dot separator", 1234567, '.', "1.234.567"},
+		{"min int64", math.MinInt64, ',', "-9,223,372,036,854,775,808"},
+		{"max int64", math.MaxInt64, ',', "9,223,372,036,854,775,807"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatWithThousands(tt.in, tt.sep); got != tt.exp {
+				t.Errorf("FormatWithThousands(%d, %q) = %q, want %q", tt.in, tt.sep, got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestFormatHumanSize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   int64
+		exp  string
+	}{
+		{"bytes", 512, "512 B"},
+		{"exact KiB", 1024, "1.00 KiB"},
+		{"KiB", 1536, "1.50 KiB"},
+		{"exact MiB", 1 << 20, "1.00 MiB"},
+		{"exact GiB", 1 << 30, "1.00 GiB"},
+		{"fractional GiB", int64(2.5 * (1 << 30)), "2.50 GiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatHumanSize(tt.in); got != tt.exp {
+				t.Errorf("FormatHumanSize(%d) = %q, want %q", tt.in, got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestParseHumanSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		exp     int64
+		wantErr bool
+	}{
+		{"plain bytes", "512", 512, false},
+		{"bytes suffix", "512 B", 512, false},
+		{"KiB", "1 KiB", 1 << 10, false},
+		{"MiB", "1 MiB", 1 << 20, false},
+		{"GiB", "1 GiB", 1 << 30, false},
+		{"fractional KiB", "1.5 KiB", 1536, false},
+		{"no space before suffix", "2MiB", 2 << 20, false},
+		{"malformed", "abc", 0, true},
+		{"malformed with suffix", "xMiB", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHumanSize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHumanSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.exp {
+				t.Errorf("ParseHumanSize(%q) = %d, want %d", tt.in, got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestHumanSizeRoundTrip(t *testing.T) {
+	for _, n := range []int64{0, 512, 1 << 10, 1 << 20, 1 << 30, 5 * (1 << 30)} {
+		formatted := FormatHumanSize(n)
+		got, err := ParseHumanSize(formatted)
+		if err != nil {
+			t.Fatalf("ParseHumanSize(%q) failed: %v", formatted, err)
+		}
+		if got != n {
+			t.Errorf("round trip for %d: FormatHumanSize -> %q -> ParseHumanSize -> %d", n, formatted, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strnum
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	rangeTokenRe = regexp.MustCompile(`^(-?\d+)-(-?\d+)$`)
+	numTokenRe   = regexp.MustCompile(`^-?\d+$`)
+)
+
+// CompactRanges collapses a sorted (ascending) slice of integers into
+// a compact human-readable form, e.g. []int{1, 2, 3, 5, 7, 8, 9}
+// becomes "1-3, 5, 7-9". It is meant for corpus/segment id lists that
+// CNC tooling frequently passes around on the CLI and in configs.
+func CompactRanges(nums []int) string {
+	if len(nums) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	first := true
+	flush := func(start, end int) {
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+		if start == end {
+			fmt.Fprintf(&b, "%d", start)
+		} else {
+			fmt.Fprintf(&b, "%d-%d", start, end)
+		}
+	}
+	start, prev := nums[0], nums[0]
+	for _, n := range nums[1:] {
+		if n == prev+1 {
+			prev = n
+			continue
+		}
+		flush(start, prev)
+		start, prev = n, n
+	}
+	flush(start, prev)
+	return b.String()
+}
+
+// MaxExpandRangesSize caps the number of integers ExpandRanges will
+// produce, so a single malformed or malicious "lo-hi" token (e.g.
+// "0-2000000000") cannot force an unbounded allocation.
+const MaxExpandRangesSize = 1_000_000
+
+// ExpandRanges is the inverse of CompactRanges - it parses a
+// comma-separated list of numbers and/or "lo-hi" ranges (e.g.
+// "1-3, 5, 7-9") into the individual integers they represent. It
+// returns an error if the expanded result would exceed
+// MaxExpandRangesSize items.
+func ExpandRanges(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	var ans []int
+	for _, tok := range strings.Split(s, ",") {
+		tok = strings.TrimSpace(tok)
+		switch {
+		case numTokenRe.MatchString(tok):
+			n, err := strconv.Atoi(tok)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q: %w", tok, err)
+			}
+			if len(ans)+1 > MaxExpandRangesSize {
+				return nil, fmt.Errorf("number %q would expand beyond the %d item limit", tok, MaxExpandRangesSize)
+			}
+			ans = append(ans, n)
+		case rangeTokenRe.MatchString(tok):
+			m := rangeTokenRe.FindStringSubmatch(tok)
+			lo, err := strconv.Atoi(m[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid range %q: %w", tok, err)
+			}
+			hi, err := strconv.Atoi(m[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid range %q: %w", tok, err)
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("invalid range %q: end before start", tok)
+			}
+			// uint(hi-lo) is the exact span even where hi-lo overflows int.
+			if span := uint(hi - lo); span >= MaxExpandRangesSize || len(ans)+int(span)+1 > MaxExpandRangesSize {
+				return nil, fmt.Errorf("range %q would expand beyond the %d item limit", tok, MaxExpandRangesSize)
+			}
+			for n := lo; n <= hi; n++ {
+				ans = append(ans, n)
+			}
+		default:
+			return nil, fmt.Errorf("malformed range token: %q", tok)
+		}
+	}
+	return ans, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strnum
+
+import (
+	"testing"
+)
+
+func TestCompactRanges(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		exp  string
+	}{
+		{"empty", nil, ""},
+		{"single", []int{5}, "5"},
+		{"consecutive", []int{1, 2, 3}, "1-3"},
+		{"mixed", []int{1, 2, 3, 5, 7, 8, 9}, "1-3, 5, 7-9"},
+		{"negative range", []int{-5, -4, -3}, "-5--3"},
+		{"negative and positive", []int{-2, -1, 0, 1}, "-2-1"},
+		{"all singles", []int{1, 3, 5}, "1, 3, 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompactRanges(tt.in)
+			if got != tt.exp {
+				t.Errorf("CompactRanges(%v) = %q, want %q", tt.in, got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestExpandRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		exp     []int
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"single", "5", []int{5}, false},
+		{"simple range", "1-3", []int{1, 2, 3}, false},
+		{"mixed", "1-3, 5, 7-9", []int{1, 2, 3, 5, 7, 8, 9}, false},
+		{"negative range", "-5--3", []int{-5, -4, -3}, false},
+		{"negative single", "-7", []int{-7}, false},
+		{"malformed range", "3-1", nil, true},
+		{"malformed token", "abc", nil, true},
+		{"empty token", "1,,3", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandRanges(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandRanges(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tt.exp) {
+				t.Fatalf("ExpandRanges(%q) = %v, want %v", tt.in, got, tt.exp)
+			}
+			for i := range got {
+				if got[i] != tt.exp[i] {
+					t.Fatalf("ExpandRanges(%q) = %v, want %v", tt.in, got, tt.exp)
+				}
+			}
+		})
+	}
+}
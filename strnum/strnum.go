@@ -16,17 +16,110 @@
 package strnum
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
 
+// Integer lists the built-in integer types usable with the generic
+// Join and Split functions.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Float lists the built-in floating point types usable with the
+// generic Join and Split functions.
+type Float interface {
+	~float32 | ~float64
+}
+
 func JoinNumbersAsString(nums []int) string {
+	return JoinNumbersAsStringSep(nums, ", ")
+}
+
+// JoinNumbersAsStringSep is like JoinNumbersAsString but lets the
+// caller choose the separator.
+func JoinNumbersAsStringSep(nums []int, sep string) string {
+	return Join(nums, sep)
+}
+
+// SplitNumbers is the inverse of JoinNumbersAsStringSep - it parses a
+// sep-separated list of integers, reporting which token failed to
+// parse, if any.
+func SplitNumbers(s string, sep string) ([]int, error) {
+	return Split[int](s, sep)
+}
+
+// Join concatenates nums, formatted as decimal numbers, using sep as
+// the separator between items.
+func Join[T Integer | Float](nums []T, sep string) string {
 	var b strings.Builder
 	for i, n := range nums {
 		if i > 0 {
-			b.WriteString(", ")
+			b.WriteString(sep)
 		}
-		b.WriteString(strconv.Itoa(n))
+		fmt.Fprintf(&b, "%v", n)
 	}
 	return b.String()
 }
+
+// Split parses a sep-separated list of numbers of type T out of s. On
+// failure, the returned error identifies the offending token and its
+// position.
+func Split[T Integer | Float](s string, sep string) ([]T, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, sep)
+	ans := make([]T, 0, len(parts))
+	var zero T
+	isFloat := false
+	isUnsigned := false
+	switch any(zero).(type) {
+	case float32, float64:
+		isFloat = true
+	case uint, uint8, uint16, uint32, uint64:
+		isUnsigned = true
+	}
+	bitSize := bitSizeOf(zero)
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		switch {
+		case isFloat:
+			v, err := strconv.ParseFloat(p, bitSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse number at position %d (%q): %w", i, p, err)
+			}
+			ans = append(ans, T(v))
+		case isUnsigned:
+			v, err := strconv.ParseUint(p, 10, bitSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse number at position %d (%q): %w", i, p, err)
+			}
+			ans = append(ans, T(v))
+		default:
+			v, err := strconv.ParseInt(p, 10, bitSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse number at position %d (%q): %w", i, p, err)
+			}
+			ans = append(ans, T(v))
+		}
+	}
+	return ans, nil
+}
+
+// bitSizeOf reports the bit width to pass to strconv's ParseInt/
+// ParseUint/ParseFloat family for T, so Split rejects out-of-range
+// tokens instead of silently truncating them.
+func bitSizeOf[T Integer | Float](zero T) int {
+	switch any(zero).(type) {
+	case int8, uint8:
+		return 8
+	case int16, uint16:
+		return 16
+	case int32, uint32, float32:
+		return 32
+	default:
+		return 64
+	}
+}
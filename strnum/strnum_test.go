@@ -0,0 +1,138 @@
+// Copyright 2024 Tomas Machalek <tomas.machalek@gmail.com>
+// Copyright 2024 Martin Zimandl <martin.zimandl@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strnum
+
+import (
+	"testing"
+)
+
+func TestJoinNumbersAsString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		exp  string
+	}{
+		{"empty", nil, ""},
+		{"single", []int{5}, "5"},
+		{"multiple", []int{1, 2, 3}, "1, 2, 3"},
+		{"negative", []int{-3, -2, -1}, "-3, -2, -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JoinNumbersAsString(tt.in); got != tt.exp {
+				t.Errorf("JoinNumbersAsString(%v) = %q, want %q", tt.in, got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestJoinNumbersAsStringSep(t *testing.T) {
+	got := JoinNumbersAsStringSep([]int{1, 2, 3}, "|")
+	if got != "1|2|3" {
+		t.Errorf("JoinNumbersAsStringSep() = %q, want %q", got, "1|2|3")
+	}
+}
+
+func TestSplitNumbers(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		sep     string
+		exp     []int
+		wantErr bool
+	}{
+		{"empty", "", ",", nil, false},
+		{"basic", "1,2,3", ",", []int{1, 2, 3}, false},
+		{"negative", "-3,-2,-1", ",", []int{-3, -2, -1}, false},
+		{"spaces", "1, 2, 3", ",", []int{1, 2, 3}, false},
+		{"malformed token", "1,x,3", ",", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitNumbers(tt.in, tt.sep)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitNumbers(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tt.exp) {
+				t.Fatalf("SplitNumbers(%q) = %v, want %v", tt.in, got, tt.exp)
+			}
+			for i := range got {
+				if got[i] != tt.exp[i] {
+					t.Fatalf("SplitNumbers(%q) = %v, want %v", tt.in, got, tt.exp)
+				}
+			}
+		})
+	}
+}
+
+func TestJoinGeneric(t *testing.T) {
+	if got := Join([]float64{1.5, -2.25, 3}, ", "); got != "1.5, -2.25, 3" {
+		t.Errorf("Join(floats) = %q, want %q", got, "1.5, -2.25, 3")
+	}
+	if got := Join([]uint8{1, 2, 255}, ","); got != "1,2,255" {
+		t.Errorf("Join(uint8) = %q, want %q", got, "1,2,255")
+	}
+}
+
+func TestSplitGeneric(t *testing.T) {
+	t.Run("float64", func(t *testing.T) {
+		got, err := Split[float64]("1.5,-2.25,3", ",")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []float64{1.5, -2.25, 3}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("Split[float64] = %v, want %v", got, want)
+			}
+		}
+	})
+
+	t.Run("int8 out of range", func(t *testing.T) {
+		if _, err := Split[int8]("200", ","); err == nil {
+			t.Fatalf("expected error for int8 overflow, got nil")
+		}
+	})
+
+	t.Run("uint rejects negative", func(t *testing.T) {
+		if _, err := Split[uint]("-1", ","); err == nil {
+			t.Fatalf("expected error for negative value parsed as uint, got nil")
+		}
+	})
+
+	t.Run("uint8 within range", func(t *testing.T) {
+		got, err := Split[uint8]("0,128,255", ",")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []uint8{0, 128, 255}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("Split[uint8] = %v, want %v", got, want)
+			}
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		got, err := Split[int]("", ",")
+		if err != nil || got != nil {
+			t.Fatalf("Split(\"\") = %v, %v, want nil, nil", got, err)
+		}
+	})
+}